@@ -0,0 +1,145 @@
+// Package broker generalizes ingestion into an action-routed fan-out: an
+// envelope {action, payload} is dispatched to whichever sink chain the
+// config declares for that action (a Store collection, a forward-HTTP
+// webhook, or both), so one POST can both persist a log and notify an
+// external system.
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+)
+
+// Envelope is the broker's request shape: an action name and its
+// free-form payload.
+type Envelope struct {
+	Action  string                 `json:"action"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// Sink delivers one Envelope somewhere: a Store collection, a webhook,
+// etc.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, env Envelope) error
+}
+
+// RetryPolicy controls how many times, and how long to wait between,
+// delivery attempts to a single sink.
+type RetryPolicy struct {
+	MaxAttempts int           `json:"max_attempts"`
+	BaseDelay   time.Duration `json:"base_delay"`
+}
+
+type route struct {
+	sinks      []Sink
+	retry      RetryPolicy
+	dryRun     bool
+	deadLetter Sink
+}
+
+// Broker dispatches envelopes to the sink chain configured for their
+// action.
+type Broker struct {
+	routes map[string]*route
+}
+
+// New builds a Broker from cfg, resolving each action's sinks against
+// backend for "store"-type sinks.
+func New(cfg Config, backend store.Store) (*Broker, error) {
+	routes := make(map[string]*route, len(cfg.Actions))
+
+	for _, a := range cfg.Actions {
+		sinks := make([]Sink, 0, len(a.Sinks))
+		for _, sc := range a.Sinks {
+			sink, err := buildSink(sc, backend)
+			if err != nil {
+				return nil, fmt.Errorf("action %q: %w", a.Action, err)
+			}
+			sinks = append(sinks, sink)
+		}
+
+		var deadLetter Sink
+		if a.DeadLetterCollection != "" {
+			deadLetter = &storeSink{store: backend, db: a.DeadLetterDB, collection: a.DeadLetterCollection}
+		}
+
+		retry := a.Retry
+		if retry.MaxAttempts <= 0 {
+			retry.MaxAttempts = 1
+		}
+		if retry.BaseDelay <= 0 {
+			retry.BaseDelay = 500 * time.Millisecond
+		}
+
+		routes[a.Action] = &route{sinks: sinks, retry: retry, dryRun: a.DryRun, deadLetter: deadLetter}
+	}
+
+	return &Broker{routes: routes}, nil
+}
+
+// Dispatch sends env to every sink configured for its action, retrying
+// each sink independently per its retry policy. Only when every sink in
+// the chain exhausts its retries is env written to the action's
+// dead-letter sink, if configured -- a partial failure (e.g. Mongo
+// succeeds but a webhook sink is down) is reported as an error but does
+// not dead-letter, since the envelope was already durably persisted.
+func (b *Broker) Dispatch(ctx context.Context, env Envelope) error {
+	rt, ok := b.routes[env.Action]
+	if !ok {
+		return fmt.Errorf("no sink route configured for action %q", env.Action)
+	}
+
+	if rt.dryRun {
+		log.Printf("broker: dryRun action %q would dispatch to %d sink(s): %+v", env.Action, len(rt.sinks), env.Payload)
+		return nil
+	}
+
+	var failures int
+	for _, sink := range rt.sinks {
+		if err := sendWithRetry(ctx, sink, env, rt.retry); err != nil {
+			log.Printf("broker: sink %s failed for action %q after retries: %v", sink.Name(), env.Action, err)
+			failures++
+		}
+	}
+
+	if failures > 0 && failures == len(rt.sinks) && rt.deadLetter != nil {
+		if dlErr := rt.deadLetter.Send(ctx, env); dlErr != nil {
+			log.Printf("broker: failed to write dead letter for action %q: %v", env.Action, dlErr)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d sinks failed for action %q", failures, len(rt.sinks), env.Action)
+	}
+
+	return nil
+}
+
+func sendWithRetry(ctx context.Context, sink Sink, env Envelope, retry RetryPolicy) error {
+	var err error
+	delay := retry.BaseDelay
+
+	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
+		if err = sink.Send(ctx, env); err == nil {
+			return nil
+		}
+
+		if attempt == retry.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+
+	return err
+}
@@ -0,0 +1,148 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSink is an in-memory Sink that records every Send call, so retry,
+// dead-letter, and dry-run behavior can be exercised without a real Store
+// or HTTP endpoint.
+type fakeSink struct {
+	mu        sync.Mutex
+	name      string
+	sends     []Envelope
+	failUntil int // the first failUntil calls return err; later calls succeed
+	err       error
+}
+
+func (f *fakeSink) Name() string { return f.name }
+
+func (f *fakeSink) Send(ctx context.Context, env Envelope) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sends = append(f.sends, env)
+	if len(f.sends) <= f.failUntil {
+		if f.err != nil {
+			return f.err
+		}
+		return errors.New("fake sink failure")
+	}
+	return nil
+}
+
+func (f *fakeSink) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.sends)
+}
+
+func TestSendWithRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	sink := &fakeSink{name: "flaky", failUntil: 2}
+	retry := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if err := sendWithRetry(context.Background(), sink, Envelope{Action: "log"}, retry); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got: %v", err)
+	}
+	if got := sink.callCount(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestSendWithRetry_ExhaustionReturnsLastError(t *testing.T) {
+	sink := &fakeSink{name: "always-down", failUntil: 100}
+	retry := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	if err := sendWithRetry(context.Background(), sink, Envelope{Action: "log"}, retry); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if got := sink.callCount(); got != 2 {
+		t.Fatalf("expected exactly MaxAttempts (2) attempts, got %d", got)
+	}
+}
+
+func TestDispatch_DryRunSendsNothing(t *testing.T) {
+	sink := &fakeSink{name: "sink"}
+	b := &Broker{routes: map[string]*route{
+		"log": {sinks: []Sink{sink}, retry: RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond}, dryRun: true},
+	}}
+
+	if err := b.Dispatch(context.Background(), Envelope{Action: "log"}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if got := sink.callCount(); got != 0 {
+		t.Fatalf("expected dryRun to send nothing, sink was called %d time(s)", got)
+	}
+}
+
+func TestDispatch_MultiSinkPartialFailureReturnsError(t *testing.T) {
+	ok := &fakeSink{name: "mongo"}
+	down := &fakeSink{name: "webhook", failUntil: 100}
+	b := &Broker{routes: map[string]*route{
+		"log": {sinks: []Sink{ok, down}, retry: RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond}},
+	}}
+
+	err := b.Dispatch(context.Background(), Envelope{Action: "log"})
+	if err == nil {
+		t.Fatal("expected an error when one of two sinks fails")
+	}
+	if got := ok.callCount(); got != 1 {
+		t.Fatalf("expected the healthy sink to still receive the envelope, got %d call(s)", got)
+	}
+	if got := down.callCount(); got != 1 {
+		t.Fatalf("expected the failing sink to be attempted once (MaxAttempts=1), got %d", got)
+	}
+}
+
+func TestDispatch_DeadLettersOnlyWhenAllSinksFail(t *testing.T) {
+	ok := &fakeSink{name: "mongo"}
+	down := &fakeSink{name: "webhook", failUntil: 100}
+	deadLetter := &fakeSink{name: "dead-letter"}
+
+	b := &Broker{routes: map[string]*route{
+		"log": {
+			sinks:      []Sink{ok, down},
+			retry:      RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond},
+			deadLetter: deadLetter,
+		},
+	}}
+
+	if err := b.Dispatch(context.Background(), Envelope{Action: "log"}); err == nil {
+		t.Fatal("expected an error when one sink fails")
+	}
+	if got := deadLetter.callCount(); got != 0 {
+		t.Fatalf("expected no dead-letter write when another sink in the chain succeeded, got %d", got)
+	}
+}
+
+func TestDispatch_DeadLettersWhenEverySinkFails(t *testing.T) {
+	down1 := &fakeSink{name: "mongo", failUntil: 100}
+	down2 := &fakeSink{name: "webhook", failUntil: 100}
+	deadLetter := &fakeSink{name: "dead-letter"}
+
+	b := &Broker{routes: map[string]*route{
+		"log": {
+			sinks:      []Sink{down1, down2},
+			retry:      RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond},
+			deadLetter: deadLetter,
+		},
+	}}
+
+	if err := b.Dispatch(context.Background(), Envelope{Action: "log"}); err == nil {
+		t.Fatal("expected an error when every sink fails")
+	}
+	if got := deadLetter.callCount(); got != 1 {
+		t.Fatalf("expected exactly 1 dead-letter write when every sink failed, got %d", got)
+	}
+}
+
+func TestDispatch_UnknownActionErrors(t *testing.T) {
+	b := &Broker{routes: map[string]*route{}}
+	if err := b.Dispatch(context.Background(), Envelope{Action: "missing"}); err == nil {
+		t.Fatal("expected an error for an action with no configured route")
+	}
+}
@@ -0,0 +1,38 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ActionRoute declares the sink chain, retry policy, and dead-letter
+// destination for one action name.
+type ActionRoute struct {
+	Action               string       `json:"action"`
+	Sinks                []SinkConfig `json:"sinks"`
+	Retry                RetryPolicy  `json:"retry"`
+	DryRun               bool         `json:"dry_run"`
+	DeadLetterDB         string       `json:"dead_letter_db,omitempty"`
+	DeadLetterCollection string       `json:"dead_letter_collection,omitempty"`
+}
+
+// Config is the top-level broker config file shape.
+type Config struct {
+	Actions []ActionRoute `json:"actions"`
+}
+
+// LoadConfig reads and parses a broker config file at path.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read broker config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse broker config: %w", err)
+	}
+
+	return cfg, nil
+}
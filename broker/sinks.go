@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+)
+
+// SinkConfig declares one sink in an action's sink chain.
+type SinkConfig struct {
+	Type       string            `json:"type"` // "store" (default) or "webhook"
+	DB         string            `json:"db,omitempty"`
+	Collection string            `json:"collection,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+// storeSink writes an envelope's payload to a Store collection.
+type storeSink struct {
+	store      store.Store
+	db         string
+	collection string
+}
+
+func (s *storeSink) Name() string {
+	return fmt.Sprintf("store:%s.%s", s.db, s.collection)
+}
+
+func (s *storeSink) Send(ctx context.Context, env Envelope) error {
+	return s.store.WriteBatch(ctx, s.db, s.collection, []map[string]interface{}{env.Payload})
+}
+
+// webhookSink forwards an envelope's raw payload as JSON to an external
+// HTTP endpoint, e.g. a SIEM ingest webhook.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook:" + s.url
+}
+
+func (s *webhookSink) Send(ctx context.Context, env Envelope) error {
+	body, err := json.Marshal(env.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func buildSink(sc SinkConfig, backend store.Store) (Sink, error) {
+	switch sc.Type {
+	case "store", "":
+		if sc.DB == "" {
+			return nil, fmt.Errorf("store sink requires db")
+		}
+		if sc.Collection == "" {
+			return nil, fmt.Errorf("store sink requires collection")
+		}
+		return &storeSink{store: backend, db: sc.DB, collection: sc.Collection}, nil
+
+	case "webhook":
+		if sc.URL == "" {
+			return nil, fmt.Errorf("webhook sink requires url")
+		}
+		return &webhookSink{url: sc.URL, headers: sc.Headers, client: &http.Client{Timeout: 10 * time.Second}}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", sc.Type)
+	}
+}
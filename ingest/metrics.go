@@ -0,0 +1,74 @@
+package ingest
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics tracks the counters exposed via the bridge's /metrics endpoint:
+// queue depth, flush latency, and dropped-doc counts.
+type Metrics struct {
+	queueDepth  int64
+	flushed     uint64
+	dropped     uint64
+	spilled     uint64
+	flushErrors uint64
+
+	flushLatencySumMillis uint64
+	flushCount            uint64
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{}
+}
+
+func (m *Metrics) incQueueDepth(delta int64) { atomic.AddInt64(&m.queueDepth, delta) }
+func (m *Metrics) incDropped(n uint64)       { atomic.AddUint64(&m.dropped, n) }
+func (m *Metrics) incSpilled(n uint64)       { atomic.AddUint64(&m.spilled, n) }
+func (m *Metrics) incFlushErrors(n uint64)   { atomic.AddUint64(&m.flushErrors, n) }
+
+func (m *Metrics) observeFlush(docs int, d time.Duration) {
+	atomic.AddUint64(&m.flushed, uint64(docs))
+	atomic.AddUint64(&m.flushLatencySumMillis, uint64(d.Milliseconds()))
+	atomic.AddUint64(&m.flushCount, 1)
+}
+
+// Handler serves the current counters in Prometheus text exposition
+// format.
+func (m *Metrics) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		flushCount := atomic.LoadUint64(&m.flushCount)
+		var avgLatencySeconds float64
+		if flushCount > 0 {
+			avgLatencySeconds = float64(atomic.LoadUint64(&m.flushLatencySumMillis)) / float64(flushCount) / 1000
+		}
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_queue_depth Documents currently in the queue, not yet picked up by a flush worker.\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_queue_depth gauge\n")
+		fmt.Fprintf(w, "fluentbitbridge_queue_depth %d\n", atomic.LoadInt64(&m.queueDepth))
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_flushed_docs_total Documents successfully flushed to the store.\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_flushed_docs_total counter\n")
+		fmt.Fprintf(w, "fluentbitbridge_flushed_docs_total %d\n", atomic.LoadUint64(&m.flushed))
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_dropped_docs_total Documents dropped because spilling them to disk also failed (full queue or failed flush, no usable spill file).\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_dropped_docs_total counter\n")
+		fmt.Fprintf(w, "fluentbitbridge_dropped_docs_total %d\n", atomic.LoadUint64(&m.dropped))
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_spilled_docs_total Documents written to the on-disk spill file because the queue was full or a flush failed.\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_spilled_docs_total counter\n")
+		fmt.Fprintf(w, "fluentbitbridge_spilled_docs_total %d\n", atomic.LoadUint64(&m.spilled))
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_flush_errors_total Batch flushes that returned an error from the store.\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_flush_errors_total counter\n")
+		fmt.Fprintf(w, "fluentbitbridge_flush_errors_total %d\n", atomic.LoadUint64(&m.flushErrors))
+
+		fmt.Fprintf(w, "# HELP fluentbitbridge_flush_latency_seconds_avg Average flush latency observed so far.\n")
+		fmt.Fprintf(w, "# TYPE fluentbitbridge_flush_latency_seconds_avg gauge\n")
+		fmt.Fprintf(w, "fluentbitbridge_flush_latency_seconds_avg %f\n", avgLatencySeconds)
+	}
+}
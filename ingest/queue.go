@@ -0,0 +1,215 @@
+// Package ingest buffers transformed log documents and flushes them to a
+// store.Store in batches, so a burst of FluentBit traffic doesn't stall on
+// a synchronous write per HTTP request.
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+)
+
+// Config controls how a Queue batches and flushes documents. Zero values
+// fall back to sensible defaults in NewQueue.
+type Config struct {
+	Workers     int
+	QueueSize   int
+	MaxDocs     int
+	MaxBytes    int
+	MaxInterval time.Duration
+	SpillPath   string
+}
+
+type doc struct {
+	db         string
+	collection string
+	body       map[string]interface{}
+	size       int
+}
+
+type batch struct {
+	db         string
+	collection string
+	docs       []map[string]interface{}
+	bytes      int
+}
+
+// Queue buffers documents in memory and flushes them to a Store in
+// batches bounded by document count, byte size, and time, via a pool of
+// worker goroutines. When the buffered channel is full, Enqueue spills the
+// document to disk instead of blocking the caller or dropping it.
+type Queue struct {
+	store store.Store
+	cfg   Config
+
+	items chan doc
+	wg    sync.WaitGroup
+
+	metrics *Metrics
+
+	spillMu   sync.Mutex
+	spillFile *os.File
+}
+
+// NewQueue creates a Queue for st using cfg.
+func NewQueue(st store.Store, cfg Config) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.MaxDocs <= 0 {
+		cfg.MaxDocs = 500
+	}
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = time.Second
+	}
+
+	return &Queue{
+		store:   st,
+		cfg:     cfg,
+		items:   make(chan doc, cfg.QueueSize),
+		metrics: newMetrics(),
+	}
+}
+
+// Metrics exposes the queue's counters, e.g. for a /metrics endpoint.
+func (q *Queue) Metrics() *Metrics {
+	return q.metrics
+}
+
+// Start launches the worker pool. Call Close to drain it and stop.
+func (q *Queue) Start() {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+}
+
+// Enqueue buffers body for db.collection. If the queue is full it spills
+// the document to disk instead of blocking; an error is only returned
+// when body cannot be marshaled to JSON or when spilling itself fails (no
+// SpillPath configured, or a disk error).
+func (q *Queue) Enqueue(db, collection string, body map[string]interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to size document: %w", err)
+	}
+
+	d := doc{db: db, collection: collection, body: body, size: len(raw)}
+
+	select {
+	case q.items <- d:
+		q.metrics.incQueueDepth(1)
+		return nil
+	default:
+		if err := q.spillWrite(d); err != nil {
+			q.metrics.incDropped(1)
+			return fmt.Errorf("queue full and spill failed: %w", err)
+		}
+		q.metrics.incSpilled(1)
+		return nil
+	}
+}
+
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	batches := make(map[string]*batch)
+	ticker := time.NewTicker(q.cfg.MaxInterval)
+	defer ticker.Stop()
+
+	flushAll := func() {
+		for key, b := range batches {
+			q.flush(b)
+			delete(batches, key)
+		}
+	}
+
+	for {
+		select {
+		case d, ok := <-q.items:
+			if !ok {
+				flushAll()
+				return
+			}
+			q.metrics.incQueueDepth(-1)
+
+			key := d.db + "\x00" + d.collection
+			b := batches[key]
+			if b == nil {
+				b = &batch{db: d.db, collection: d.collection}
+				batches[key] = b
+			}
+			b.docs = append(b.docs, d.body)
+			b.bytes += d.size
+
+			if len(b.docs) >= q.cfg.MaxDocs || (q.cfg.MaxBytes > 0 && b.bytes >= q.cfg.MaxBytes) {
+				q.flush(b)
+				delete(batches, key)
+			}
+
+		case <-ticker.C:
+			flushAll()
+		}
+	}
+}
+
+func (q *Queue) flush(b *batch) {
+	if len(b.docs) == 0 {
+		return
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := q.store.WriteBatch(ctx, b.db, b.collection, b.docs); err != nil {
+		log.Printf("ingest: failed to flush %d docs to %s.%s: %v", len(b.docs), b.db, b.collection, err)
+		q.metrics.incFlushErrors(1)
+		q.spillBatch(b)
+		return
+	}
+
+	q.metrics.observeFlush(len(b.docs), time.Since(start))
+}
+
+// spillBatch writes every doc in b to the spill file after a failed
+// flush (e.g. Mongo unreachable), so they can be replayed on next startup
+// instead of being silently lost. A doc is only counted as dropped if it
+// can't be spilled either (no SpillPath configured, or a disk error).
+func (q *Queue) spillBatch(b *batch) {
+	for _, body := range b.docs {
+		if err := q.spillWrite(doc{db: b.db, collection: b.collection, body: body}); err != nil {
+			q.metrics.incDropped(1)
+			continue
+		}
+		q.metrics.incSpilled(1)
+	}
+}
+
+// Close stops accepting new documents and waits for in-flight batches to
+// drain, up to ctx's deadline.
+func (q *Queue) Close(ctx context.Context) error {
+	close(q.items)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return q.closeSpill()
+	case <-ctx.Done():
+		return fmt.Errorf("ingest queue drain deadline exceeded: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type writeBatchCall struct {
+	db, collection string
+	docs           []map[string]interface{}
+}
+
+// fakeStore is an in-memory store.Store that records WriteBatch calls
+// instead of touching a real database, so queue trigger logic can be
+// exercised without Mongo/Elasticsearch/file deps.
+type fakeStore struct {
+	mu      sync.Mutex
+	batches []writeBatchCall
+	err     error
+}
+
+func (f *fakeStore) WriteBatch(ctx context.Context, db, collection string, docs []map[string]interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	cp := make([]map[string]interface{}, len(docs))
+	copy(cp, docs)
+	f.batches = append(f.batches, writeBatchCall{db: db, collection: collection, docs: cp})
+	return nil
+}
+
+func (f *fakeStore) Ping(ctx context.Context) error  { return nil }
+func (f *fakeStore) Close(ctx context.Context) error { return nil }
+
+func (f *fakeStore) waitForBatches(t *testing.T, n int, timeout time.Duration) []writeBatchCall {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		f.mu.Lock()
+		got := len(f.batches)
+		if got >= n {
+			out := append([]writeBatchCall(nil), f.batches...)
+			f.mu.Unlock()
+			return out
+		}
+		f.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d batch(es)", n)
+	return nil
+}
+
+func closeQueue(t *testing.T, q *Queue) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := q.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestQueue_FlushesOnMaxDocs(t *testing.T) {
+	st := &fakeStore{}
+	q := NewQueue(st, Config{Workers: 1, MaxDocs: 3, MaxInterval: time.Hour})
+	q.Start()
+	defer closeQueue(t, q)
+
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue("db", "coll", map[string]interface{}{"n": i}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	batches := st.waitForBatches(t, 1, time.Second)
+	if len(batches[0].docs) != 3 {
+		t.Fatalf("expected a batch of 3 docs, got %d", len(batches[0].docs))
+	}
+}
+
+func TestQueue_FlushesOnMaxBytes(t *testing.T) {
+	st := &fakeStore{}
+	// Each doc below marshals to 25 bytes, so one alone doesn't cross
+	// MaxBytes but two together do, forcing a flush before MaxDocs (set
+	// deliberately high) ever would.
+	q := NewQueue(st, Config{Workers: 1, MaxDocs: 1000, MaxBytes: 30, MaxInterval: time.Hour})
+	q.Start()
+	defer closeQueue(t, q)
+
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue("db", "coll", map[string]interface{}{"message": "hello world"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	batches := st.waitForBatches(t, 1, time.Second)
+	if len(batches[0].docs) != 2 {
+		t.Fatalf("expected the byte threshold to flush both buffered docs together, got %d", len(batches[0].docs))
+	}
+}
+
+func TestQueue_FlushesOnInterval(t *testing.T) {
+	st := &fakeStore{}
+	q := NewQueue(st, Config{Workers: 1, MaxDocs: 1000, MaxInterval: 20 * time.Millisecond})
+	q.Start()
+	defer closeQueue(t, q)
+
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	batches := st.waitForBatches(t, 1, time.Second)
+	if len(batches[0].docs) != 1 {
+		t.Fatalf("expected the ticker to flush the single buffered doc, got %d", len(batches[0].docs))
+	}
+}
+
+func TestQueue_SpillsWhenChannelFull(t *testing.T) {
+	st := &fakeStore{}
+	spillPath := t.TempDir() + "/spill.jsonl"
+	q := NewQueue(st, Config{Workers: 1, QueueSize: 1, MaxDocs: 1000, MaxInterval: time.Hour, SpillPath: spillPath})
+	// Deliberately not calling Start: nothing drains q.items, so the
+	// second Enqueue beyond QueueSize must spill instead of blocking.
+
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("first Enqueue should fit in the buffered channel: %v", err)
+	}
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 2}); err != nil {
+		t.Fatalf("second Enqueue should spill rather than error: %v", err)
+	}
+
+	if got := q.Metrics().spilled; got != 1 {
+		t.Fatalf("expected 1 spilled doc, got %d", got)
+	}
+
+	if err := q.closeSpill(); err != nil {
+		t.Fatalf("closeSpill: %v", err)
+	}
+
+	replayed := &fakeStore{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ReplaySpill(ctx, replayed, spillPath); err != nil {
+		t.Fatalf("ReplaySpill: %v", err)
+	}
+	if len(replayed.batches) != 1 || len(replayed.batches[0].docs) != 1 {
+		t.Fatalf("expected the spilled doc to replay, got %+v", replayed.batches)
+	}
+}
+
+func TestQueue_FlushFailureSpillsBatchInsteadOfDroppingIt(t *testing.T) {
+	st := &fakeStore{err: errors.New("mongo unreachable")}
+	spillPath := t.TempDir() + "/spill.jsonl"
+	q := NewQueue(st, Config{Workers: 1, MaxDocs: 1, MaxInterval: time.Hour, SpillPath: spillPath})
+	q.Start()
+
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadUint64(&q.metrics.spilled) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the failed flush to spill its doc")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := atomic.LoadUint64(&q.metrics.flushErrors); got != 1 {
+		t.Fatalf("expected 1 flush error, got %d", got)
+	}
+	if got := atomic.LoadUint64(&q.metrics.dropped); got != 0 {
+		t.Fatalf("expected the doc to be spilled rather than dropped, got %d dropped", got)
+	}
+
+	closeQueue(t, q)
+
+	replayed := &fakeStore{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ReplaySpill(ctx, replayed, spillPath); err != nil {
+		t.Fatalf("ReplaySpill: %v", err)
+	}
+	if len(replayed.batches) != 1 || len(replayed.batches[0].docs) != 1 {
+		t.Fatalf("expected the spilled doc from the failed flush to replay, got %+v", replayed.batches)
+	}
+}
+
+func TestQueue_EnqueueErrorsWhenFullWithNoSpillConfigured(t *testing.T) {
+	st := &fakeStore{}
+	q := NewQueue(st, Config{Workers: 1, QueueSize: 1, MaxDocs: 1000, MaxInterval: time.Hour})
+
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("first Enqueue should fit in the buffered channel: %v", err)
+	}
+	if err := q.Enqueue("db", "coll", map[string]interface{}{"n": 2}); err == nil {
+		t.Fatal("expected an error once the channel is full and no spill path is configured")
+	}
+	if got := q.Metrics().dropped; got != 1 {
+		t.Fatalf("expected 1 dropped doc, got %d", got)
+	}
+}
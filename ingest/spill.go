@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+)
+
+// spillRecord is the on-disk representation of one document appended to
+// the spill file when the queue is full or a flush to the store fails.
+type spillRecord struct {
+	DB         string                 `json:"db"`
+	Collection string                 `json:"collection"`
+	Doc        map[string]interface{} `json:"doc"`
+}
+
+func (q *Queue) spillWrite(d doc) error {
+	if q.cfg.SpillPath == "" {
+		return fmt.Errorf("spill file not configured")
+	}
+
+	q.spillMu.Lock()
+	defer q.spillMu.Unlock()
+
+	if q.spillFile == nil {
+		f, err := os.OpenFile(q.cfg.SpillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open spill file: %w", err)
+		}
+		q.spillFile = f
+	}
+
+	return json.NewEncoder(q.spillFile).Encode(spillRecord{DB: d.db, Collection: d.collection, Doc: d.body})
+}
+
+func (q *Queue) closeSpill() error {
+	q.spillMu.Lock()
+	defer q.spillMu.Unlock()
+
+	if q.spillFile != nil {
+		return q.spillFile.Close()
+	}
+	return nil
+}
+
+// ReplaySpill reads any documents left over in the spill file from a prior
+// run (e.g. the store was unreachable at shutdown), writes them straight
+// to st, and truncates the file. Call it once at startup before the queue
+// begins accepting new traffic.
+func ReplaySpill(ctx context.Context, st store.Store, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open spill file: %w", err)
+	}
+	defer f.Close()
+
+	var replayed int
+	dec := json.NewDecoder(f)
+	for {
+		var rec spillRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to decode spill record: %w", err)
+		}
+
+		if err := st.WriteBatch(ctx, rec.DB, rec.Collection, []map[string]interface{}{rec.Doc}); err != nil {
+			return fmt.Errorf("failed to replay spilled document: %w", err)
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		log.Printf("ingest: replayed %d spilled documents from %s", replayed, path)
+	}
+
+	return os.Truncate(path, 0)
+}
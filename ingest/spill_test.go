@@ -0,0 +1,51 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReplaySpill_MissingFileIsNotAnError(t *testing.T) {
+	st := &fakeStore{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := ReplaySpill(ctx, st, t.TempDir()+"/does-not-exist.jsonl"); err != nil {
+		t.Fatalf("expected a missing spill file to be a no-op, got: %v", err)
+	}
+	if len(st.batches) != 0 {
+		t.Fatalf("expected no writes, got %+v", st.batches)
+	}
+}
+
+func TestReplaySpill_TruncatesAfterReplay(t *testing.T) {
+	st := &fakeStore{}
+	spillPath := t.TempDir() + "/spill.jsonl"
+
+	q := NewQueue(st, Config{SpillPath: spillPath})
+	if err := q.spillWrite(doc{db: "db", collection: "coll", body: map[string]interface{}{"n": 1}}); err != nil {
+		t.Fatalf("spillWrite: %v", err)
+	}
+	if err := q.closeSpill(); err != nil {
+		t.Fatalf("closeSpill: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ReplaySpill(ctx, st, spillPath); err != nil {
+		t.Fatalf("ReplaySpill: %v", err)
+	}
+	if len(st.batches) != 1 {
+		t.Fatalf("expected the spilled doc to be replayed, got %+v", st.batches)
+	}
+
+	info, err := os.Stat(spillPath)
+	if err != nil {
+		t.Fatalf("stat spill file: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Fatalf("expected the spill file to be truncated after replay, size is %d", info.Size())
+	}
+}
@@ -7,11 +7,20 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"github.com/mtmazurik/fluent-bit-bridge/broker"
+	"github.com/mtmazurik/fluent-bit-bridge/ingest"
+	"github.com/mtmazurik/fluent-bit-bridge/migrations"
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+	"github.com/mtmazurik/fluent-bit-bridge/store/elasticstore"
+	"github.com/mtmazurik/fluent-bit-bridge/store/filestore"
+	"github.com/mtmazurik/fluent-bit-bridge/store/mongostore"
+	"github.com/mtmazurik/fluent-bit-bridge/tenant"
 )
 
 type LogLevel string
@@ -49,60 +58,288 @@ type Log struct {
 }
 
 type Server struct {
-	client    *mongo.Client
-	apiKey    string
+	store       store.Store
+	queue       *ingest.Queue
+	tenants     *tenant.Registry
+	rateLimiter *tenant.Limiter
+	broker      *broker.Broker
+	apiKey      string
 	defaultDB string
 	defaultCollection string
 }
 
 func NewServer() (*Server, error) {
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		return nil, fmt.Errorf("MONGODB_URI environment variable required")
-	}
-
-	apiKey := os.Getenv("API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("API_KEY environment variable required")
-	}
-
 	defaultDB := os.Getenv("MONGODB_DB")
 	if defaultDB == "" {
 		defaultDB = "logging"
 	}
 
-	defaultCollection := os.Getenv("MONGODB_COLLECTION") 
+	defaultCollection := os.Getenv("MONGODB_COLLECTION")
 	if defaultCollection == "" {
 		defaultCollection = "logs"
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	backend, err := newStoreBackend(defaultDB, defaultCollection)
+	if err != nil {
+		return nil, err
+	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	queue, err := newIngestQueue(backend)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+		return nil, err
 	}
+	queue.Start()
 
-	// Test connection
-	err = client.Ping(ctx, nil)
+	tenants, err := loadTenantRegistry(backend, defaultDB)
 	if err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+		return nil, err
+	}
+
+	apiKey := os.Getenv("API_KEY")
+	if tenants == nil && apiKey == "" {
+		return nil, fmt.Errorf("API_KEY environment variable required")
 	}
 
-	log.Printf("Connected to MongoDB successfully")
+	b, err := loadBroker(backend)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Server{
-		client:            client,
+		store:             backend,
+		queue:             queue,
+		tenants:           tenants,
+		rateLimiter:       tenant.NewLimiter(),
+		broker:            b,
 		apiKey:            apiKey,
 		defaultDB:         defaultDB,
 		defaultCollection: defaultCollection,
 	}, nil
 }
 
-func (s *Server) authenticate(r *http.Request) bool {
+// loadBroker builds the action-routed ingest broker from the config file at
+// BROKER_CONFIG, if set. It returns a nil Broker (not an error) when unset,
+// in which case /broker responds 501.
+func loadBroker(backend store.Store) (*broker.Broker, error) {
+	path := os.Getenv("BROKER_CONFIG")
+	if path == "" {
+		return nil, nil
+	}
+
+	cfg, err := broker.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := broker.New(cfg, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build broker: %w", err)
+	}
+
+	return b, nil
+}
+
+// loadTenantRegistry builds the multi-tenant API key registry from
+// API_KEYS_FILE or the TENANTS_COLLECTION in defaultDB, whichever is
+// configured. It returns a nil Registry (not an error) when neither is
+// set, in which case the bridge falls back to the single API_KEY check.
+func loadTenantRegistry(backend store.Store, defaultDB string) (*tenant.Registry, error) {
+	if path := os.Getenv("API_KEYS_FILE"); path != "" {
+		return tenant.LoadFromFile(path)
+	}
+
+	collection := os.Getenv("TENANTS_COLLECTION")
+	if collection == "" {
+		return nil, nil
+	}
+
+	mongoStore, ok := backend.(*mongostore.Store)
+	if !ok {
+		return nil, fmt.Errorf("TENANTS_COLLECTION requires STORE_BACKEND=mongo")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return tenant.LoadFromMongo(ctx, mongoStore.Client().Database(defaultDB), collection)
+}
+
+// newIngestQueue builds the batching queue that sits in front of backend,
+// replaying any documents spilled to disk on a prior run before accepting
+// new traffic.
+func newIngestQueue(backend store.Store) (*ingest.Queue, error) {
+	cfg := ingest.Config{
+		Workers:     envInt("BATCH_WORKERS", 4),
+		MaxDocs:     envInt("BATCH_MAX_DOCS", 500),
+		MaxBytes:    envInt("BATCH_MAX_BYTES", 0),
+		MaxInterval: envDuration("BATCH_MAX_INTERVAL", time.Second),
+		SpillPath:   os.Getenv("SPILL_FILE"),
+	}
+
+	if cfg.SpillPath != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := ingest.ReplaySpill(ctx, backend, cfg.SpillPath); err != nil {
+			return nil, fmt.Errorf("failed to replay spill file: %w", err)
+		}
+	}
+
+	return ingest.NewQueue(backend, cfg), nil
+}
+
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %d", key, v, def)
+		return def
+	}
+
+	return n
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %s", key, v, def)
+		return def
+	}
+
+	return d
+}
+
+// newStoreBackend selects and constructs the configured storage backend.
+// STORE_BACKEND defaults to "mongo" to match the bridge's original
+// behavior; "elasticsearch" and "file"/"stdout" are also supported so
+// operators can pick a backend suited to their retention/query needs.
+func newStoreBackend(defaultDB, defaultCollection string) (store.Store, error) {
+	backend := os.Getenv("STORE_BACKEND")
+	if backend == "" {
+		backend = "mongo"
+	}
+
+	switch backend {
+	case "mongo":
+		mongoURI := os.Getenv("MONGODB_URI")
+		if mongoURI == "" {
+			return nil, fmt.Errorf("MONGODB_URI environment variable required")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		s, err := mongostore.New(ctx, mongoURI)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("Connected to MongoDB successfully")
+
+		if err := runMigrations(s, defaultDB, defaultCollection); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+
+		return s, nil
+
+	case "elasticsearch":
+		esURL := os.Getenv("ELASTICSEARCH_URL")
+		if esURL == "" {
+			return nil, fmt.Errorf("ELASTICSEARCH_URL environment variable required")
+		}
+		return elasticstore.New(esURL), nil
+
+	case "file", "stdout":
+		s, err := filestore.New(os.Getenv("FILE_STORE_PATH"))
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+
+	default:
+		return nil, fmt.Errorf("unknown STORE_BACKEND %q", backend)
+	}
+}
+
+// runMigrations applies the required indexes to defaultDB.defaultCollection
+// before the server starts accepting ingest traffic. LOG_TTL, when set,
+// installs a TTL index on timestamp (e.g. "720h"); changing or clearing it
+// on a later startup re-provisions the index accordingly.
+func runMigrations(s *mongostore.Store, defaultDB, defaultCollection string) error {
+	var ttl time.Duration
+	if ttlStr := os.Getenv("LOG_TTL"); ttlStr != "" {
+		parsed, err := time.ParseDuration(ttlStr)
+		if err != nil {
+			return fmt.Errorf("invalid LOG_TTL %q: %w", ttlStr, err)
+		}
+		ttl = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db := s.Client().Database(defaultDB)
+	return migrations.Run(ctx, db, defaultCollection, migrations.Default(ttl))
+}
+
+// authenticate validates the request's X-API-Key and, when a tenant
+// registry is configured, returns the Tenant it maps to. With no registry
+// configured it falls back to the single API_KEY check and returns a nil
+// Tenant, meaning "no tenant scoping applies".
+func (s *Server) authenticate(r *http.Request) (*tenant.Tenant, bool) {
 	apiKey := r.Header.Get("X-API-Key")
-	return apiKey == s.apiKey
+	if apiKey == "" {
+		return nil, false
+	}
+
+	if s.tenants != nil {
+		return s.tenants.Lookup(apiKey)
+	}
+
+	return nil, apiKey == s.apiKey
+}
+
+// resolveScope determines the effective db/collection for a request and
+// enforces the tenant's allow-list. When the tenant didn't request a
+// collection explicitly, it defaults to <defaultCollection>_<tenant_id>
+// rather than the shared default, so one tenant's logs never land in
+// another's collection. A non-empty return value is a 403 reason.
+func resolveScope(t *tenant.Tenant, r *http.Request, defaultDB, defaultCollection string) (db, collection, forbidden string) {
+	db = r.URL.Query().Get("db")
+	if db == "" {
+		db = defaultDB
+	}
+
+	collection = r.URL.Query().Get("collection")
+
+	if t == nil {
+		if collection == "" {
+			collection = defaultCollection
+		}
+		return db, collection, ""
+	}
+
+	if !t.AllowsDB(db) {
+		return "", "", "db not allowed for tenant"
+	}
+
+	if collection != "" {
+		if !t.AllowsCollection(collection) {
+			return "", "", "collection not allowed for tenant"
+		}
+		return db, collection, ""
+	}
+
+	return db, fmt.Sprintf("%s_%s", defaultCollection, t.TenantID), ""
 }
 
 func (s *Server) ingestHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,20 +348,21 @@ func (s *Server) ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !s.authenticate(r) {
+	t, ok := s.authenticate(r)
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	// Get database and collection from query params
-	db := r.URL.Query().Get("db")
-	if db == "" {
-		db = s.defaultDB
+	if t != nil && !s.rateLimiter.Allow(t) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
 	}
 
-	collection := r.URL.Query().Get("collection") 
-	if collection == "" {
-		collection = s.defaultCollection
+	db, collection, forbidden := resolveScope(t, r, s.defaultDB, s.defaultCollection)
+	if forbidden != "" {
+		http.Error(w, "Forbidden: "+forbidden, http.StatusForbidden)
+		return
 	}
 
 	// Parse incoming JSON
@@ -150,7 +388,7 @@ func (s *Server) ingestHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Process each log entry
-	var documents []interface{}
+	var documents []map[string]interface{}
 	for _, logEntry := range logs {
 		logMap, ok := logEntry.(map[string]interface{})
 		if !ok {
@@ -159,6 +397,9 @@ func (s *Server) ingestHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Transform FluentBit log to our structured format
 		processedLog := s.transformLog(logMap)
+		if t != nil {
+			processedLog["tenant_id"] = t.TenantID
+		}
 		documents = append(documents, processedLog)
 	}
 
@@ -167,29 +408,20 @@ func (s *Server) ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Insert into MongoDB
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	coll := s.client.Database(db).Collection(collection)
-	
-	if len(documents) == 1 {
-		_, err := coll.InsertOne(ctx, documents[0])
-		if err != nil {
-			log.Printf("Failed to insert log: %v", err)
-			http.Error(w, "Database error", http.StatusInternalServerError)
-			return
-		}
-	} else {
-		_, err := coll.InsertMany(ctx, documents)
-		if err != nil {
-			log.Printf("Failed to insert logs: %v", err)
-			http.Error(w, "Database error", http.StatusInternalServerError)
-			return
+	var failed int
+	for _, document := range documents {
+		if err := s.queue.Enqueue(db, collection, document); err != nil {
+			log.Printf("Failed to enqueue log: %v", err)
+			failed++
 		}
 	}
 
-	log.Printf("Inserted %d logs into %s.%s", len(documents), db, collection)
+	if failed == len(documents) {
+		http.Error(w, "Queue error", http.StatusServiceUnavailable)
+		return
+	}
+
+	log.Printf("Accepted %d logs for %s.%s", len(documents)-failed, db, collection)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -256,7 +488,7 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	err := s.client.Ping(ctx, nil)
+	err := s.store.Ping(ctx)
 	if err != nil {
 		log.Printf("Health check failed: %v", err)
 		http.Error(w, "Database unhealthy", http.StatusServiceUnavailable)
@@ -271,21 +503,314 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// brokerHandler serves POST /broker, accepting a broker.Envelope and
+// dispatching it to the sink chain configured for its action. It requires
+// BROKER_CONFIG to have been set at startup.
+func (s *Server) brokerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t, ok := s.authenticate(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if t != nil && !s.rateLimiter.Allow(t) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	if s.broker == nil {
+		http.Error(w, "Broker not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var env broker.Envelope
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		log.Printf("Failed to decode broker envelope: %v", err)
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if env.Action == "" {
+		http.Error(w, "action is required", http.StatusBadRequest)
+		return
+	}
+
+	if t != nil {
+		if env.Payload == nil {
+			env.Payload = make(map[string]interface{})
+		}
+		env.Payload["tenant_id"] = t.TenantID
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := s.broker.Dispatch(ctx, env); err != nil {
+		log.Printf("Broker dispatch failed for action %q: %v", env.Action, err)
+		http.Error(w, "Dispatch error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// logsHandler serves GET /logs?service=&level=&trace_id=&since=&until=&limit=&cursor=,
+// returning a page of matching documents plus an opaque cursor for
+// keyset pagination. It requires a Store backend that implements
+// store.Queryable.
+func (s *Server) logsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t, ok := s.authenticate(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if t != nil && !s.rateLimiter.Allow(t) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	queryable, ok := s.store.(store.Queryable)
+	if !ok {
+		http.Error(w, "Log querying not supported by the configured store backend", http.StatusNotImplemented)
+		return
+	}
+
+	db, collection, forbidden := resolveScope(t, r, s.defaultDB, s.defaultCollection)
+	if forbidden != "" {
+		http.Error(w, "Forbidden: "+forbidden, http.StatusForbidden)
+		return
+	}
+
+	filter, err := parseLogFilter(r, db, collection)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	page, err := queryable.Query(ctx, filter)
+	if err != nil {
+		log.Printf("Failed to query logs: %v", err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results": page.Documents,
+		"cursor":  page.Cursor,
+	})
+}
+
+// parseLogFilter translates /logs query params into a store.Filter scoped
+// to db/collection.
+func parseLogFilter(r *http.Request, db, collection string) (store.Filter, error) {
+	q := r.URL.Query()
+
+	filter := store.Filter{
+		DB:         db,
+		Collection: collection,
+		Service:    q.Get("service"),
+		Level:      q.Get("level"),
+		TraceID:    q.Get("trace_id"),
+		Cursor:     q.Get("cursor"),
+	}
+
+	if since := q.Get("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return store.Filter{}, fmt.Errorf("invalid since: %w", err)
+		}
+		filter.Since = parsed
+	}
+
+	if until := q.Get("until"); until != "" {
+		parsed, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return store.Filter{}, fmt.Errorf("invalid until: %w", err)
+		}
+		filter.Until = parsed
+	}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return store.Filter{}, fmt.Errorf("invalid limit: %w", err)
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
+
+// documentCursor extracts a backend-defined cursor value from a queried
+// document's _id, without main.go needing to import any particular store
+// backend's driver types. Backends that support Queryable are expected to
+// return an _id that exposes Hex() string (e.g. mongo's primitive.ObjectID).
+func documentCursor(doc map[string]interface{}) (string, bool) {
+	id, ok := doc["_id"].(interface{ Hex() string })
+	if !ok {
+		return "", false
+	}
+	return id.Hex(), true
+}
+
+// tailHandler serves GET /logs/tail?service=&level=, upgrading to
+// Server-Sent Events and streaming newly matching documents by polling
+// with a _id > lastSeen cursor once per second. lastSeen is tracked from
+// the last document this handler actually sent, not from Page.Cursor:
+// Cursor is scoped to "is there another page" for /logs pagination and is
+// left empty whenever a poll returns fewer than Limit documents, which is
+// the steady-state case here. The first poll has no cursor, so a new
+// stream starts from the oldest matching document and sends existing
+// matches, up to Limit per poll, before it reaches new ones.
+func (s *Server) tailHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t, ok := s.authenticate(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if t != nil && !s.rateLimiter.Allow(t) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	queryable, ok := s.store.(store.Queryable)
+	if !ok {
+		http.Error(w, "Log querying not supported by the configured store backend", http.StatusNotImplemented)
+		return
+	}
+
+	db, collection, forbidden := resolveScope(t, r, s.defaultDB, s.defaultCollection)
+	if forbidden != "" {
+		http.Error(w, "Forbidden: "+forbidden, http.StatusForbidden)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	filter := store.Filter{
+		DB:         db,
+		Collection: collection,
+		Service:    r.URL.Query().Get("service"),
+		Level:      r.URL.Query().Get("level"),
+		Limit:      100,
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-ticker.C:
+			if t != nil && !s.rateLimiter.Allow(t) {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			page, err := queryable.Query(ctx, filter)
+			cancel()
+			if err != nil {
+				log.Printf("Failed to tail logs: %v", err)
+				continue
+			}
+
+			for _, doc := range page.Documents {
+				data, err := json.Marshal(doc)
+				if err != nil {
+					log.Printf("Failed to encode tailed log: %v", err)
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+
+			if n := len(page.Documents); n > 0 {
+				if lastSeen, ok := documentCursor(page.Documents[n-1]); ok {
+					filter.Cursor = lastSeen
+				}
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
 func main() {
 	server, err := NewServer()
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
 	}
 
-	http.HandleFunc("/ingest", server.ingestHandler)
-	http.HandleFunc("/healthz", server.healthHandler)
-	http.HandleFunc("/health", server.healthHandler)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ingest", server.ingestHandler)
+	mux.HandleFunc("/healthz", server.healthHandler)
+	mux.HandleFunc("/health", server.healthHandler)
+	mux.HandleFunc("/metrics", server.queue.Metrics().Handler())
+	mux.HandleFunc("/logs", server.logsHandler)
+	mux.HandleFunc("/logs/tail", server.tailHandler)
+	mux.HandleFunc("/broker", server.brokerHandler)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("Starting fluent-bit-bridge server on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	httpServer := &http.Server{
+		Addr:    ":" + port,
+		Handler: mux,
+	}
+
+	go func() {
+		log.Printf("Starting fluent-bit-bridge server on port %s", port)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTP server error: %v", err)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	<-sigCh
+
+	log.Printf("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
+	}
+
+	if err := server.queue.Close(shutdownCtx); err != nil {
+		log.Printf("Queue drain error: %v", err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,211 @@
+// Package migrations guarantees that the MongoDB indexes the bridge
+// depends on exist before it starts serving ingest traffic. Each
+// Migration is versioned and idempotent: applied versions are recorded in
+// a schema_migrations collection so re-runs on every startup only apply
+// what's new.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const migrationsCollection = "schema_migrations"
+
+// ttlIndexName is fixed so the LOG_TTL migration can find and drop its own
+// index regardless of the expireAfterSeconds value it was created with.
+const ttlIndexName = "timestamp_ttl"
+
+// Migration is a single schema change, applied at most once per Version.
+// Params is compared against the previously recorded value for that
+// Version; when it differs (e.g. LOG_TTL changed), Down is run against the
+// old state before Up re-applies it.
+type Migration struct {
+	Version int
+	Name    string
+	Params  string
+	Up      func(ctx context.Context, db *mongo.Database, collection string) error
+	Down    func(ctx context.Context, db *mongo.Database, collection string) error
+}
+
+type appliedRecord struct {
+	Version   int       `bson:"version"`
+	Name      string    `bson:"name"`
+	Params    string    `bson:"params"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Default returns the bridge's built-in migrations for collection: the
+// core indexes required for efficient querying, plus a TTL index governed
+// by ttl (zero disables it). Migrations must stay in ascending Version
+// order; append new ones rather than editing existing Up funcs.
+func Default(ttl time.Duration) []Migration {
+	return []Migration{
+		{
+			Version: 1,
+			Name:    "timestamp_desc_index",
+			Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+				_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "timestamp", Value: -1}},
+				})
+				return err
+			},
+		},
+		{
+			Version: 2,
+			Name:    "service_timestamp_index",
+			Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+				_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "service", Value: 1}, {Key: "timestamp", Value: -1}},
+				})
+				return err
+			},
+		},
+		{
+			Version: 3,
+			Name:    "level_timestamp_index",
+			Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+				_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys: bson.D{{Key: "level", Value: 1}, {Key: "timestamp", Value: -1}},
+				})
+				return err
+			},
+		},
+		{
+			Version: 4,
+			Name:    "trace_id_sparse_index",
+			Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+				_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "trace_id", Value: 1}},
+					Options: options.Index().SetSparse(true),
+				})
+				return err
+			},
+		},
+		ttlMigration(ttl),
+	}
+}
+
+// ttlMigration installs (or removes) a TTL index on timestamp. ttl <= 0
+// disables it. Params encodes the configured duration so Run re-provisions
+// the index whenever LOG_TTL changes.
+func ttlMigration(ttl time.Duration) Migration {
+	params := "disabled"
+	if ttl > 0 {
+		params = ttl.String()
+	}
+
+	return Migration{
+		Version: 5,
+		Name:    "timestamp_ttl_index",
+		Params:  params,
+		Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+			if ttl <= 0 {
+				return nil
+			}
+			_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys: bson.D{{Key: "timestamp", Value: 1}},
+				Options: options.Index().
+					SetName(ttlIndexName).
+					SetExpireAfterSeconds(int32(ttl.Seconds())),
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database, collection string) error {
+			_, err := db.Collection(collection).Indexes().DropOne(ctx, ttlIndexName)
+			if err != nil && isIndexNotFoundErr(err) {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// Run applies any migrations whose Version is not yet recorded in db's
+// schema_migrations collection, in order, recording each as it succeeds.
+// Records are keyed by Version alone, not by collection. It fails fast on the first error so a
+// broken migration never leaves later ones applied against a partial
+// state.
+func Run(ctx context.Context, db *mongo.Database, collection string, migrations []Migration) error {
+	records := &mongoRecordStore{coll: db.Collection(migrationsCollection)}
+	return run(ctx, records, db, collection, migrations)
+}
+
+// recordStore looks up and persists the bookkeeping Run uses to decide
+// whether a migration is new, unchanged, or needs a down+up re-apply.
+// Pulling it out behind an interface lets run's branching be exercised
+// against a fake in tests, without a live MongoDB deployment.
+type recordStore interface {
+	get(ctx context.Context, version int) (appliedRecord, bool, error)
+	set(ctx context.Context, rec appliedRecord) error
+}
+
+type mongoRecordStore struct {
+	coll *mongo.Collection
+}
+
+func (r *mongoRecordStore) get(ctx context.Context, version int) (appliedRecord, bool, error) {
+	var existing appliedRecord
+	err := r.coll.FindOne(ctx, bson.M{"version": version}).Decode(&existing)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		return appliedRecord{}, false, nil
+	case err != nil:
+		return appliedRecord{}, false, err
+	}
+	return existing, true, nil
+}
+
+func (r *mongoRecordStore) set(ctx context.Context, rec appliedRecord) error {
+	_, err := r.coll.UpdateOne(ctx,
+		bson.M{"version": rec.Version},
+		bson.M{"$set": rec},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func run(ctx context.Context, records recordStore, db *mongo.Database, collection string, migrations []Migration) error {
+	for _, m := range migrations {
+		existing, ok, err := records.get(ctx, m.Version)
+
+		switch {
+		case err != nil:
+			return fmt.Errorf("migration %d (%s): failed to check applied state: %w", m.Version, m.Name, err)
+		case !ok:
+			// Not yet applied.
+		case existing.Params == m.Params:
+			log.Printf("migration %d (%s) already applied, skipping", m.Version, m.Name)
+			continue
+		default:
+			if m.Down != nil {
+				if err := m.Down(ctx, db, collection); err != nil {
+					return fmt.Errorf("migration %d (%s): down failed: %w", m.Version, m.Name, err)
+				}
+			}
+		}
+
+		if err := m.Up(ctx, db, collection); err != nil {
+			return fmt.Errorf("migration %d (%s): up failed: %w", m.Version, m.Name, err)
+		}
+
+		if err := records.set(ctx, appliedRecord{Version: m.Version, Name: m.Name, Params: m.Params, AppliedAt: time.Now()}); err != nil {
+			return fmt.Errorf("migration %d (%s): failed to record applied state: %w", m.Version, m.Name, err)
+		}
+
+		log.Printf("applied migration %d (%s)", m.Version, m.Name)
+	}
+
+	return nil
+}
+
+func isIndexNotFoundErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "index not found")
+}
@@ -0,0 +1,168 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// fakeRecordStore is an in-memory recordStore, so run's skip/down+up
+// branching can be tested without a live MongoDB deployment.
+type fakeRecordStore struct {
+	records map[int]appliedRecord
+	getErr  error
+}
+
+func newFakeRecordStore() *fakeRecordStore {
+	return &fakeRecordStore{records: make(map[int]appliedRecord)}
+}
+
+func (f *fakeRecordStore) get(ctx context.Context, version int) (appliedRecord, bool, error) {
+	if f.getErr != nil {
+		return appliedRecord{}, false, f.getErr
+	}
+	rec, ok := f.records[version]
+	return rec, ok, nil
+}
+
+func (f *fakeRecordStore) set(ctx context.Context, rec appliedRecord) error {
+	f.records[rec.Version] = rec
+	return nil
+}
+
+func noopMigration(version int, params string) (Migration, *int, *int) {
+	ups, downs := 0, 0
+	return Migration{
+		Version: version,
+		Name:    "test_migration",
+		Params:  params,
+		Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+			ups++
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database, collection string) error {
+			downs++
+			return nil
+		},
+	}, &ups, &downs
+}
+
+func TestRun_AppliesNewMigration(t *testing.T) {
+	records := newFakeRecordStore()
+	m, ups, _ := noopMigration(1, "")
+
+	if err := run(context.Background(), records, nil, "logs", []Migration{m}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if *ups != 1 {
+		t.Fatalf("expected Up to run once, ran %d times", *ups)
+	}
+	if _, ok := records.records[1]; !ok {
+		t.Fatal("expected the migration to be recorded as applied")
+	}
+}
+
+func TestRun_SkipsAlreadyAppliedMigrationWithSameParams(t *testing.T) {
+	records := newFakeRecordStore()
+	records.records[1] = appliedRecord{Version: 1, Name: "test_migration", Params: "ttl=1h"}
+	m, ups, downs := noopMigration(1, "ttl=1h")
+
+	if err := run(context.Background(), records, nil, "logs", []Migration{m}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if *ups != 0 || *downs != 0 {
+		t.Fatalf("expected an already-applied migration with unchanged params to be skipped, ups=%d downs=%d", *ups, *downs)
+	}
+}
+
+func TestRun_ParamChangeTriggersDownThenUp(t *testing.T) {
+	records := newFakeRecordStore()
+	records.records[1] = appliedRecord{Version: 1, Name: "test_migration", Params: "ttl=1h"}
+	m, ups, downs := noopMigration(1, "ttl=2h")
+
+	if err := run(context.Background(), records, nil, "logs", []Migration{m}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if *downs != 1 {
+		t.Fatalf("expected Down to run once when Params changed, ran %d times", *downs)
+	}
+	if *ups != 1 {
+		t.Fatalf("expected Up to re-apply once Down has run, ran %d times", *ups)
+	}
+	if got := records.records[1].Params; got != "ttl=2h" {
+		t.Fatalf("expected the recorded params to be updated to %q, got %q", "ttl=2h", got)
+	}
+}
+
+func TestRun_ParamChangeWithNilDownStillReapplies(t *testing.T) {
+	records := newFakeRecordStore()
+	records.records[1] = appliedRecord{Version: 1, Name: "test_migration", Params: "ttl=1h"}
+	ups := 0
+	m := Migration{
+		Version: 1,
+		Name:    "test_migration",
+		Params:  "ttl=2h",
+		Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+			ups++
+			return nil
+		},
+	}
+
+	if err := run(context.Background(), records, nil, "logs", []Migration{m}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if ups != 1 {
+		t.Fatalf("expected Up to run once, ran %d times", ups)
+	}
+}
+
+func TestRun_StopsAtFirstFailureAndLeavesLaterMigrationsUnapplied(t *testing.T) {
+	records := newFakeRecordStore()
+	failing := Migration{
+		Version: 1,
+		Name:    "failing_migration",
+		Up: func(ctx context.Context, db *mongo.Database, collection string) error {
+			return errors.New("boom")
+		},
+	}
+	later, laterUps, _ := noopMigration(2, "")
+
+	err := run(context.Background(), records, nil, "logs", []Migration{failing, later})
+	if err == nil {
+		t.Fatal("expected run to fail when a migration's Up errors")
+	}
+	if *laterUps != 0 {
+		t.Fatal("expected a later migration to never run once an earlier one failed")
+	}
+	if _, ok := records.records[1]; ok {
+		t.Fatal("expected the failing migration to not be recorded as applied")
+	}
+}
+
+func TestRun_GetErrorIsPropagated(t *testing.T) {
+	records := newFakeRecordStore()
+	records.getErr = errors.New("connection refused")
+	m, _, _ := noopMigration(1, "")
+
+	if err := run(context.Background(), records, nil, "logs", []Migration{m}); err == nil {
+		t.Fatal("expected a lookup error to be propagated")
+	}
+}
+
+func TestTTLMigration_ParamsEncodeDuration(t *testing.T) {
+	disabled := ttlMigration(0)
+	if disabled.Params != "disabled" {
+		t.Fatalf("expected a zero TTL to encode as %q, got %q", "disabled", disabled.Params)
+	}
+
+	enabled := ttlMigration(24 * time.Hour)
+	if enabled.Params != (24 * time.Hour).String() {
+		t.Fatalf("expected the TTL duration to be encoded in Params, got %q", enabled.Params)
+	}
+}
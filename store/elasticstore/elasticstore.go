@@ -0,0 +1,100 @@
+// Package elasticstore implements store.Store against an Elasticsearch
+// cluster's HTTP bulk API, so operators who already run the ELK stack can
+// point the bridge at it without a separate ingest pipeline.
+package elasticstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Store writes documents to Elasticsearch via the _bulk endpoint.
+type Store struct {
+	addr   string
+	client *http.Client
+}
+
+// New returns a Store that talks to the Elasticsearch cluster at addr
+// (e.g. "http://localhost:9200").
+func New(addr string) *Store {
+	return &Store{
+		addr:   strings.TrimSuffix(addr, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// indexName derives an Elasticsearch index from the logical db/collection
+// pair, since Elasticsearch has no notion of a database.
+func indexName(db, collection string) string {
+	return db + "_" + collection
+}
+
+// WriteBatch bulk-indexes docs into the index derived from db and
+// collection.
+func (s *Store) WriteBatch(ctx context.Context, db, collection string, docs []map[string]interface{}) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	index := indexName(db, collection)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, d := range docs {
+		action := map[string]interface{}{"index": map[string]string{"_index": index}}
+		if err := enc.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := enc.Encode(d); err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.addr+"/_bulk", &buf)
+	if err != nil {
+		return fmt.Errorf("failed to build bulk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("elasticsearch bulk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch bulk write failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Ping checks that the Elasticsearch cluster is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/_cluster/health", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("elasticsearch health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch health check failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close is a no-op since Store holds no persistent connection.
+func (s *Store) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package filestore implements store.Store by appending newline-delimited
+// JSON to a local file (or stdout), intended for local development and
+// debugging FluentBit output without a running database.
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Store writes documents as JSONL to an underlying writer.
+type Store struct {
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File
+}
+
+// New opens path for appending and returns a Store backed by it. An empty
+// path or "stdout" writes to standard output instead of a file.
+func New(path string) (*Store, error) {
+	if path == "" || path == "stdout" {
+		return &Store{w: os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file store at %q: %w", path, err)
+	}
+
+	return &Store{w: f, file: f}, nil
+}
+
+// WriteBatch appends one JSON line per document, each wrapping the
+// document with the db/collection it was addressed to.
+func (s *Store) WriteBatch(ctx context.Context, db, collection string, docs []map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	for _, d := range docs {
+		record := map[string]interface{}{
+			"db":         db,
+			"collection": collection,
+			"doc":        d,
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to write document: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Ping always succeeds; there is no remote dependency to check.
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close closes the underlying file, if any.
+func (s *Store) Close(ctx context.Context) error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package mongostore implements store.Store on top of the official
+// MongoDB Go driver. This is the original, and still default, backend.
+package mongostore
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mtmazurik/fluent-bit-bridge/store"
+)
+
+// Store writes documents to MongoDB collections.
+type Store struct {
+	client *mongo.Client
+}
+
+// New connects to the MongoDB deployment at uri and verifies the
+// connection with a ping before returning.
+func New(ctx context.Context, uri string) (*Store, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	return &Store{client: client}, nil
+}
+
+// Client exposes the underlying driver client for callers that need
+// Mongo-specific functionality (e.g. the migrations subsystem).
+func (s *Store) Client() *mongo.Client {
+	return s.client
+}
+
+// WriteBatch inserts docs into db.collection, using InsertOne for a single
+// document and a BulkWrite of InsertOneModel operations otherwise so
+// large batches flush in a single round trip.
+func (s *Store) WriteBatch(ctx context.Context, db, collection string, docs []map[string]interface{}) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	coll := s.client.Database(db).Collection(collection)
+
+	if len(docs) == 1 {
+		_, err := coll.InsertOne(ctx, docs[0])
+		return err
+	}
+
+	models := make([]mongo.WriteModel, len(docs))
+	for i, d := range docs {
+		models[i] = mongo.NewInsertOneModel().SetDocument(d)
+	}
+
+	_, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// defaultQueryLimit caps result pages when the caller doesn't specify one.
+const defaultQueryLimit = 100
+
+// Query implements store.Queryable using keyset pagination on _id: results
+// are sorted ascending by _id, and f.Cursor (when set) is the hex _id of
+// the last document a caller already saw.
+func (s *Store) Query(ctx context.Context, f store.Filter) (store.Page, error) {
+	coll := s.client.Database(f.DB).Collection(f.Collection)
+
+	filter := bson.D{}
+	if f.Service != "" {
+		filter = append(filter, bson.E{Key: "service", Value: f.Service})
+	}
+	if f.Level != "" {
+		filter = append(filter, bson.E{Key: "level", Value: f.Level})
+	}
+	if f.TraceID != "" {
+		filter = append(filter, bson.E{Key: "trace_id", Value: f.TraceID})
+	}
+
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts := bson.D{}
+		if !f.Since.IsZero() {
+			ts = append(ts, bson.E{Key: "$gte", Value: f.Since})
+		}
+		if !f.Until.IsZero() {
+			ts = append(ts, bson.E{Key: "$lte", Value: f.Until})
+		}
+		filter = append(filter, bson.E{Key: "timestamp", Value: ts})
+	}
+
+	if f.Cursor != "" {
+		id, err := primitive.ObjectIDFromHex(f.Cursor)
+		if err != nil {
+			return store.Page{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}})
+	}
+
+	limit := int64(f.Limit)
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+
+	cur, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return store.Page{}, fmt.Errorf("failed to query logs: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var docs []map[string]interface{}
+	var lastID primitive.ObjectID
+	for cur.Next(ctx) {
+		var doc bson.M
+		if err := cur.Decode(&doc); err != nil {
+			return store.Page{}, fmt.Errorf("failed to decode log: %w", err)
+		}
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			lastID = id
+		}
+		docs = append(docs, doc)
+	}
+	if err := cur.Err(); err != nil {
+		return store.Page{}, fmt.Errorf("failed to iterate logs: %w", err)
+	}
+
+	page := store.Page{Documents: docs}
+	if int64(len(docs)) == limit {
+		page.Cursor = lastID.Hex()
+	}
+
+	return page, nil
+}
+
+// Ping checks that the MongoDB deployment is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx, nil)
+}
+
+// Close disconnects the underlying client.
+func (s *Store) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
@@ -0,0 +1,52 @@
+// Package store defines the persistence backend that ingested documents are
+// written to. Concrete implementations live in subpackages (mongostore,
+// elasticstore, filestore) and are selected at startup via the
+// STORE_BACKEND env var, keeping the HTTP layer decoupled from any one
+// database driver.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Store writes batches of transformed log documents to a backing store and
+// reports its own health. db and collection are logical names; backends
+// that have no native notion of either (e.g. file) may ignore them or fold
+// them into a derived path/index name.
+type Store interface {
+	WriteBatch(ctx context.Context, db, collection string, docs []map[string]interface{}) error
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Filter scopes a log query. Since/Until are zero when unset. Cursor is an
+// opaque, backend-defined pagination token; Limit <= 0 lets the backend
+// choose a default.
+type Filter struct {
+	DB         string
+	Collection string
+	Service    string
+	Level      string
+	TraceID    string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Cursor     string
+}
+
+// Page is one page of query results, plus the cursor to fetch the next
+// page. Cursor is empty when the page holds fewer documents than the
+// limit; a full page carries a cursor even if no further results exist.
+type Page struct {
+	Documents []map[string]interface{}
+	Cursor    string
+}
+
+// Queryable is implemented by Store backends that can serve reads back out
+// (the /logs API). Not every backend can — e.g. filestore has nothing to
+// query — so callers should type-assert for it rather than requiring it
+// on Store itself.
+type Queryable interface {
+	Query(ctx context.Context, f Filter) (Page, error)
+}
@@ -0,0 +1,70 @@
+package tenant
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a classic token-bucket limiter: it refills at rate
+// tokens/sec up to capacity, and each Allow() call spends one token.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	// Hold at least one token so a rate below 1/sec still admits requests.
+	capacity := math.Max(rate, 1)
+	return &tokenBucket{tokens: capacity, capacity: capacity, rate: rate, last: time.Now()}
+}
+
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// Limiter rate-limits requests per tenant, keyed by TenantID, so a noisy
+// tenant cannot starve others.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewLimiter returns an empty Limiter; buckets are created lazily per
+// tenant on first use.
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// Allow reports whether a request for t may proceed. A Tenant with
+// RateLimit <= 0 is unlimited.
+func (l *Limiter) Allow(t *Tenant) bool {
+	if t.RateLimit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	b, ok := l.buckets[t.TenantID]
+	if !ok {
+		b = newTokenBucket(t.RateLimit)
+		l.buckets[t.TenantID] = b
+	}
+	l.mu.Unlock()
+
+	return b.Allow()
+}
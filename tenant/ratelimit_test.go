@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_AllowsUpToCapacityThenBlocks(t *testing.T) {
+	l := NewLimiter()
+	tenant := &Tenant{TenantID: "acme", RateLimit: 3}
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow(tenant) {
+			t.Fatalf("call %d: expected Allow to succeed within capacity", i)
+		}
+	}
+
+	if l.Allow(tenant) {
+		t.Fatal("expected Allow to block once the bucket is drained")
+	}
+}
+
+func TestLimiter_RefillsOverTime(t *testing.T) {
+	l := NewLimiter()
+	tenant := &Tenant{TenantID: "acme", RateLimit: 10}
+
+	for l.Allow(tenant) {
+		// Drain the bucket.
+	}
+
+	time.Sleep(150 * time.Millisecond) // ~1.5 tokens at 10/sec
+
+	if !l.Allow(tenant) {
+		t.Fatal("expected a refilled token to be available after waiting")
+	}
+}
+
+func TestLimiter_UnlimitedWhenRateLimitIsZero(t *testing.T) {
+	l := NewLimiter()
+	tenant := &Tenant{TenantID: "acme", RateLimit: 0}
+
+	for i := 0; i < 100; i++ {
+		if !l.Allow(tenant) {
+			t.Fatalf("call %d: expected an unlimited tenant to never be throttled", i)
+		}
+	}
+}
+
+func TestLimiter_TenantsAreIndependent(t *testing.T) {
+	l := NewLimiter()
+	acme := &Tenant{TenantID: "acme", RateLimit: 1}
+	globex := &Tenant{TenantID: "globex", RateLimit: 1}
+
+	if !l.Allow(acme) {
+		t.Fatal("expected acme's first request to be allowed")
+	}
+	if l.Allow(acme) {
+		t.Fatal("expected acme's bucket to be drained after one request")
+	}
+	if !l.Allow(globex) {
+		t.Fatal("expected globex to have its own, unaffected bucket")
+	}
+}
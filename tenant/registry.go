@@ -0,0 +1,68 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Registry looks up a Tenant by its API key.
+type Registry struct {
+	mu    sync.RWMutex
+	byKey map[string]*Tenant
+}
+
+func newRegistry(tenants []Tenant) *Registry {
+	r := &Registry{byKey: make(map[string]*Tenant, len(tenants))}
+	for i := range tenants {
+		t := tenants[i]
+		r.byKey[t.APIKey] = &t
+	}
+	return r
+}
+
+// Lookup returns the Tenant for apiKey, if any.
+func (r *Registry) Lookup(apiKey string) (*Tenant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.byKey[apiKey]
+	return t, ok
+}
+
+// LoadFromFile reads a JSON array of Tenant records from path. YAML is not
+// supported yet; API_KEYS_FILE must be a .json file.
+func LoadFromFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file: %w", err)
+	}
+
+	return newRegistry(tenants), nil
+}
+
+// LoadFromMongo reads tenant records from a MongoDB collection.
+func LoadFromMongo(ctx context.Context, db *mongo.Database, collection string) (*Registry, error) {
+	cur, err := db.Collection(collection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants collection: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var tenants []Tenant
+	if err := cur.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants collection: %w", err)
+	}
+
+	return newRegistry(tenants), nil
+}
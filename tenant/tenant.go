@@ -0,0 +1,36 @@
+// Package tenant implements multi-tenant authentication: a set of API keys
+// each scoped to the databases/collections they may write to and a
+// per-tenant rate limit, loaded from a JSON file or a MongoDB collection.
+package tenant
+
+// Tenant is a single set of credentials and the scope they're allowed to
+// operate in. An empty AllowedDBs/AllowedCollections means "all allowed".
+type Tenant struct {
+	APIKey             string   `json:"api_key" bson:"api_key"`
+	TenantID           string   `json:"tenant_id" bson:"tenant_id"`
+	AllowedDBs         []string `json:"allowed_dbs" bson:"allowed_dbs"`
+	AllowedCollections []string `json:"allowed_collections" bson:"allowed_collections"`
+	RateLimit          float64  `json:"rate_limit" bson:"rate_limit"` // requests/sec; 0 = unlimited
+}
+
+// AllowsDB reports whether t may write to db.
+func (t *Tenant) AllowsDB(db string) bool {
+	return allowed(t.AllowedDBs, db)
+}
+
+// AllowsCollection reports whether t may write to collection.
+func (t *Tenant) AllowsCollection(collection string) bool {
+	return allowed(t.AllowedCollections, collection)
+}
+
+func allowed(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}